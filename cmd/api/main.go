@@ -14,38 +14,76 @@ import (
 	"product-catalog/internal/cache"
 	"product-catalog/internal/config"
 	"product-catalog/internal/handlers"
+	"product-catalog/internal/jobs"
 	"product-catalog/internal/middleware"
 	"product-catalog/internal/repository"
+	"product-catalog/internal/service"
+	"product-catalog/internal/validation"
 )
 
 func main() {
 	// Configurar modo de Gin
 	gin.SetMode(gin.ReleaseMode)
 
+	// Cargar configuración (config.toml + variables de entorno)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("❌ Failed to load configuration: %v", err)
+	}
+
 	// Inicializar base de datos
 	if err := config.InitDB(); err != nil {
 		log.Fatalf("❌ Failed to initialize database: %v", err)
 	}
 	defer config.Close()
 
-	// Inicializar caché
-	cache.Init(5 * time.Minute)
+	// Inicializar caché y suscribirla a recargas de configuración en caliente
+	cache.Init(cfg.Cache.DefaultTTL)
+	config.OnReload(func(c *config.AppConfig) {
+		cache.Get().SetDefaultTTL(c.Cache.DefaultTTL)
+	})
 	log.Println("✅ Cache initialized successfully")
 
+	// Umbral de rate limiting, también suscrito a recargas en caliente
+	middleware.SetRateLimit(cfg.RateLimit.RequestsPerMinute)
+	config.OnReload(func(c *config.AppConfig) {
+		middleware.SetRateLimit(c.RateLimit.RequestsPerMinute)
+	})
+
 	// Inicializar repositorio y handler
 	productRepo := repository.NewProductRepository(config.Collection)
+	categoryRepo := repository.NewCategoryRepository(config.CategoryCollection)
+	if err := validation.RegisterCategoryExists(categoryRepo); err != nil {
+		log.Fatalf("❌ Failed to register category validation rule: %v", err)
+	}
 	productHandler := handlers.NewProductHandler(productRepo)
+	categoryHandler := handlers.NewCategoryHandler(categoryRepo)
+	adminHandler := handlers.NewAdminHandler()
+	productService := service.NewProductService(config.Client, config.Collection, config.CategoryCollection)
+	transactionHandler := handlers.NewTransactionHandler(productService)
+
+	// Inicializar y arrancar el scheduler de background jobs
+	scheduler := jobs.NewScheduler()
+	if err := scheduler.Register(cfg.Jobs.CacheWarmerSchedule, jobs.NewCacheWarmerJob(productRepo, cache.Get(), cfg.Jobs.CacheWarmerTopN, 2*time.Minute)); err != nil {
+		log.Fatalf("❌ Failed to register cache warmer job: %v", err)
+	}
+	if err := scheduler.Register(cfg.Jobs.PurgeSchedule, jobs.NewPurgeJob(productRepo, cfg.Jobs.PurgeRetention)); err != nil {
+		log.Fatalf("❌ Failed to register purge job: %v", err)
+	}
+	if err := scheduler.Register(cfg.Jobs.SweepSchedule, jobs.NewSweepJob(cache.Get())); err != nil {
+		log.Fatalf("❌ Failed to register cache sweep job: %v", err)
+	}
+	scheduler.Start()
+	jobsHandler := handlers.NewJobsHandler(scheduler)
+	log.Println("✅ Background jobs scheduled")
 
 	// Configurar router
-	router := setupRouter(productHandler)
-
-	// Puerto
-	port := getEnv("PORT", "8080")
+	router := setupRouter(cfg, productHandler, categoryHandler, adminHandler, transactionHandler, jobsHandler)
 
 	// Servidor con graceful shutdown
 	go func() {
-		log.Printf("🚀 Server running on http://localhost:%s\n", port)
-		if err := router.Run(":" + port); err != nil {
+		log.Printf("🚀 Server running on http://localhost:%s\n", cfg.Server.Port)
+		if err := router.Run(":" + cfg.Server.Port); err != nil {
 			log.Fatalf("❌ Failed to start server: %v", err)
 		}
 	}()
@@ -56,20 +94,22 @@ func main() {
 	<-quit
 
 	log.Println("🛑 Shutting down server...")
+	scheduler.Stop(cfg.Jobs.DrainTimeout)
 	cache.Get().Clear()
 	log.Println("✅ Server stopped gracefully")
 }
 
-func setupRouter(handler *handlers.ProductHandler) *gin.Engine {
+func setupRouter(cfg *config.AppConfig, handler *handlers.ProductHandler, categories *handlers.CategoryHandler, admin *handlers.AdminHandler, txn *handlers.TransactionHandler, jobsHandler *handlers.JobsHandler) *gin.Engine {
 	router := gin.New()
 
 	// Middlewares globales
-	router.Use(gin.Logger())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.RequestLogger(middleware.NewRequestLoggerConfig(cfg.Log)))
 	router.Use(gin.Recovery())
-	
+
 	// CORS
 	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
+		AllowOrigins:     cfg.CORS.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
@@ -80,8 +120,8 @@ func setupRouter(handler *handlers.ProductHandler) *gin.Engine {
 	// Compresión GZIP
 	router.Use(gzip.Gzip(gzip.DefaultCompression))
 
-	// Rate limiting (100 requests por minuto por IP)
-	router.Use(middleware.RateLimiter(100))
+	// Rate limiting, umbral leído en caliente de config.toml
+	router.Use(middleware.RateLimiter())
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -103,10 +143,38 @@ func setupRouter(handler *handlers.ProductHandler) *gin.Engine {
 		{
 			products.POST("", handler.CreateProduct)
 			products.GET("", handler.ListProducts)
+			products.GET("/search", handler.SearchProducts)
+			products.GET("/facets", handler.GetFacets)
 			products.GET("/:id", handler.GetProduct)
 			products.PATCH("/:id", handler.UpdateProduct)
 			products.DELETE("/:id", handler.DeleteProduct)
 		}
+		v1.POST("/products:bulk", handler.BulkWrite)
+		v1.POST("/products:adjust-stock", txn.AdjustStock)
+
+		categoryGroup := v1.Group("/categories")
+		{
+			categoryGroup.POST("", categories.CreateCategory)
+			categoryGroup.GET("", categories.ListCategories)
+			categoryGroup.GET("/tree", categories.GetCategoryTree)
+			categoryGroup.GET("/:id", categories.GetCategory)
+			categoryGroup.PATCH("/:id", categories.UpdateCategory)
+			categoryGroup.DELETE("/:id", categories.DeleteCategory)
+			categoryGroup.POST("/:name/rename", txn.RenameCategory)
+		}
+
+		adminGroup := v1.Group("/admin")
+		{
+			adminGroup.GET("/cache/stats", admin.CacheStats)
+			adminGroup.POST("/cache/flush", admin.FlushCache)
+
+			jobsGroup := adminGroup.Group("/jobs")
+			jobsGroup.Use(middleware.AdminAuth(cfg.Admin.Token))
+			{
+				jobsGroup.GET("", jobsHandler.ListJobs)
+				jobsGroup.POST("/:name/run", jobsHandler.RunJob)
+			}
+		}
 	}
 
 	// 404 handler
@@ -115,11 +183,4 @@ func setupRouter(handler *handlers.ProductHandler) *gin.Engine {
 	})
 
 	return router
-}
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package cache
 import (
 	"encoding/json"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,9 +13,11 @@ type CacheItem struct {
 }
 
 type Cache struct {
-	items map[string]CacheItem
-	mu    sync.RWMutex
-	ttl   time.Duration
+	items  map[string]CacheItem
+	mu     sync.RWMutex
+	ttl    atomic.Int64 // time.Duration, actualizable en caliente vía SetDefaultTTL
+	hits   uint64
+	misses uint64
 }
 
 var (
@@ -27,14 +30,20 @@ func Init(defaultTTL time.Duration) *Cache {
 	once.Do(func() {
 		Instance = &Cache{
 			items: make(map[string]CacheItem),
-			ttl:   defaultTTL,
 		}
+		Instance.ttl.Store(int64(defaultTTL))
 		// Limpiar caché expirado cada 5 minutos
 		go Instance.cleanupExpired()
 	})
 	return Instance
 }
 
+// SetDefaultTTL actualiza el TTL usado por Set cuando no se pasa uno explícito,
+// para que un cambio de configuración en caliente aplique sin reiniciar el proceso.
+func (c *Cache) SetDefaultTTL(ttl time.Duration) {
+	c.ttl.Store(int64(ttl))
+}
+
 // Get obtiene la instancia global del caché
 func Get() *Cache {
 	if Instance == nil {
@@ -48,7 +57,7 @@ func (c *Cache) Set(key string, value interface{}, ttl ...time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	duration := c.ttl
+	duration := time.Duration(c.ttl.Load())
 	if len(ttl) > 0 {
 		duration = ttl[0]
 	}
@@ -66,18 +75,25 @@ func (c *Cache) GetValue(key string) (interface{}, bool) {
 	defer c.mu.RUnlock()
 
 	item, found := c.items[key]
-	if !found {
-		return nil, false
-	}
-
-	// Verificar si expiró
-	if time.Now().UnixNano() > item.Expiration {
+	if !found || time.Now().UnixNano() > item.Expiration {
+		atomic.AddUint64(&c.misses, 1)
 		return nil, false
 	}
 
+	atomic.AddUint64(&c.hits, 1)
 	return item.Value, true
 }
 
+// Hits retorna el número de lecturas que encontraron la clave en caché
+func (c *Cache) Hits() uint64 {
+	return atomic.LoadUint64(&c.hits)
+}
+
+// Misses retorna el número de lecturas que no encontraron la clave en caché
+func (c *Cache) Misses() uint64 {
+	return atomic.LoadUint64(&c.misses)
+}
+
 // Delete elimina un valor del caché
 func (c *Cache) Delete(key string) {
 	c.mu.Lock()
@@ -110,15 +126,26 @@ func (c *Cache) cleanupExpired() {
 	defer ticker.Stop()
 
 	for range ticker.C {
-		c.mu.Lock()
-		now := time.Now().UnixNano()
-		for key, item := range c.items {
-			if now > item.Expiration {
-				delete(c.items, key)
-			}
+		c.Sweep()
+	}
+}
+
+// Sweep compacta el caché eliminando de inmediato las entradas ya expiradas y
+// devuelve cuántas borró; lo usa cleanupExpired en su ciclo periódico y el job
+// de background jobs.SweepJob para forzar una pasada bajo demanda.
+func (c *Cache) Sweep() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now().UnixNano()
+	removed := 0
+	for key, item := range c.items {
+		if now > item.Expiration {
+			delete(c.items, key)
+			removed++
 		}
-		c.mu.Unlock()
 	}
+	return removed
 }
 
 // Size retorna el número de items en caché
@@ -155,4 +182,4 @@ func (c *Cache) Unmarshal(key string, target interface{}) (bool, error) {
 	}
 
 	return true, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// ServerConfig agrupa la configuración de arranque del servidor HTTP
+type ServerConfig struct {
+	Port string `mapstructure:"port"`
+}
+
+// DbMasterConfig describe la conexión al nodo principal de MongoDB
+type DbMasterConfig struct {
+	URI            string        `mapstructure:"uri"`
+	Name           string        `mapstructure:"name"`
+	ConnectTimeout time.Duration `mapstructure:"connect_timeout"`
+	ReadTimeout    time.Duration `mapstructure:"read_timeout"`
+}
+
+// DbConfig agrupa las conexiones a bases de datos; por ahora sólo Master
+type DbConfig struct {
+	Master DbMasterConfig `mapstructure:"master"`
+}
+
+// CacheConfig trae el TTL por defecto del caché y overrides por endpoint
+type CacheConfig struct {
+	DefaultTTL   time.Duration            `mapstructure:"default_ttl"`
+	TTLOverrides map[string]time.Duration `mapstructure:"ttl_overrides"`
+}
+
+// TTL devuelve el override configurado para endpoint en ttl_overrides, o
+// DefaultTTL si no hay ninguno; los handlers la llaman en cada petición para
+// heredar los cambios de config.toml sin reiniciar el proceso.
+func (c CacheConfig) TTL(endpoint string) time.Duration {
+	if ttl, ok := c.TTLOverrides[endpoint]; ok {
+		return ttl
+	}
+	return c.DefaultTTL
+}
+
+// RateLimitConfig trae el umbral de peticiones por minuto por IP
+type RateLimitConfig struct {
+	RequestsPerMinute int `mapstructure:"requests_per_minute"`
+}
+
+// CORSConfig trae los orígenes permitidos por el middleware de CORS
+type CORSConfig struct {
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+}
+
+// LogConfig configura el nivel y el destino del logger de peticiones
+type LogConfig struct {
+	Level      string `mapstructure:"level"`
+	FilePath   string `mapstructure:"file_path"`
+	JSON       bool   `mapstructure:"json"`
+	MaxSizeMB  int    `mapstructure:"max_size_mb"`
+	MaxBackups int    `mapstructure:"max_backups"`
+	MaxAgeDays int    `mapstructure:"max_age_days"`
+}
+
+// JobsConfig configura el scheduler de jobs en background (cache warmer,
+// purga de soft-deletes y barrido de caché expirado).
+type JobsConfig struct {
+	CacheWarmerSchedule string        `mapstructure:"cache_warmer_schedule"`
+	CacheWarmerTopN     int           `mapstructure:"cache_warmer_top_n"`
+	PurgeSchedule       string        `mapstructure:"purge_schedule"`
+	PurgeRetention      time.Duration `mapstructure:"purge_retention"`
+	SweepSchedule       string        `mapstructure:"sweep_schedule"`
+	DrainTimeout        time.Duration `mapstructure:"drain_timeout"`
+}
+
+// AdminConfig trae el token del stub de autenticación de las rutas administrativas
+type AdminConfig struct {
+	Token string `mapstructure:"token"`
+}
+
+// AppConfig es la configuración completa de la aplicación, cargada desde
+// config.toml y superpuesta por variables de entorno.
+type AppConfig struct {
+	Server    ServerConfig    `mapstructure:"server"`
+	Db        DbConfig        `mapstructure:"db"`
+	Cache     CacheConfig     `mapstructure:"cache"`
+	RateLimit RateLimitConfig `mapstructure:"ratelimit"`
+	CORS      CORSConfig      `mapstructure:"cors"`
+	Log       LogConfig       `mapstructure:"log"`
+	Jobs      JobsConfig      `mapstructure:"jobs"`
+	Admin     AdminConfig     `mapstructure:"admin"`
+}
+
+var (
+	current     atomic.Pointer[AppConfig]
+	subscribers []func(*AppConfig)
+	subMu       sync.Mutex
+)
+
+// Load lee config.toml (si existe) superpuesto por variables de entorno, valida
+// que los campos requeridos estén presentes y deja el resultado disponible vía
+// Current(). Activa viper.WatchConfig para recargar en caliente y notificar a
+// los subscribers registrados con OnReload.
+func Load() (*AppConfig, error) {
+	v := viper.New()
+	v.SetConfigName("config")
+	v.SetConfigType("toml")
+	v.AddConfigPath(".")
+
+	v.SetEnvPrefix("APP")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	setDefaults(v)
+
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return nil, fmt.Errorf("error reading config.toml: %w", err)
+		}
+	}
+
+	cfg, err := decodeConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	current.Store(cfg)
+
+	v.WatchConfig()
+	v.OnConfigChange(func(_ fsnotify.Event) {
+		reloaded, err := decodeConfig(v)
+		if err != nil {
+			log.Printf("⚠️  Failed to reload config.toml: %v", err)
+			return
+		}
+		current.Store(reloaded)
+		notifySubscribers(reloaded)
+		log.Println("✅ Configuration reloaded")
+	})
+
+	return cfg, nil
+}
+
+// setDefaults fija los valores usados si config.toml no define la clave
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("server.port", "8080")
+
+	v.SetDefault("db.master.uri", "mongodb+srv://<username>:<password>@cluster0.mongodb.net/?retryWrites=true&w=majority")
+	v.SetDefault("db.master.name", "product_catalog")
+	v.SetDefault("db.master.connect_timeout", 10*time.Second)
+	v.SetDefault("db.master.read_timeout", 30*time.Second)
+
+	v.SetDefault("cache.default_ttl", 5*time.Minute)
+
+	v.SetDefault("ratelimit.requests_per_minute", 100)
+
+	v.SetDefault("cors.allowed_origins", []string{"*"})
+
+	v.SetDefault("log.level", "info")
+	v.SetDefault("log.file_path", "storage/logs/app.log")
+	v.SetDefault("log.json", false)
+	v.SetDefault("log.max_size_mb", 100)
+	v.SetDefault("log.max_backups", 5)
+	v.SetDefault("log.max_age_days", 28)
+
+	v.SetDefault("jobs.cache_warmer_schedule", "@every 5m")
+	v.SetDefault("jobs.cache_warmer_top_n", 10)
+	v.SetDefault("jobs.purge_schedule", "@every 1h")
+	v.SetDefault("jobs.purge_retention", 720*time.Hour)
+	v.SetDefault("jobs.sweep_schedule", "@every 10m")
+	v.SetDefault("jobs.drain_timeout", 30*time.Second)
+
+	v.SetDefault("admin.token", "changeme")
+}
+
+// decodeConfig vuelca lo cargado por viper en AppConfig y valida los campos requeridos
+func decodeConfig(v *viper.Viper) (*AppConfig, error) {
+	var cfg AppConfig
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("error unmarshaling config: %w", err)
+	}
+
+	if err := validateRequired(&cfg); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// validateRequired falla rápido si faltan los campos sin los que la app no puede operar
+func validateRequired(cfg *AppConfig) error {
+	if cfg.Db.Master.URI == "" {
+		return fmt.Errorf("config: db.master.uri is required")
+	}
+	if cfg.Db.Master.Name == "" {
+		return fmt.Errorf("config: db.master.name is required")
+	}
+	if cfg.RateLimit.RequestsPerMinute <= 0 {
+		return fmt.Errorf("config: ratelimit.requests_per_minute must be positive")
+	}
+	return nil
+}
+
+// Current devuelve la configuración vigente, incluyendo recargas en caliente
+func Current() *AppConfig {
+	return current.Load()
+}
+
+// OnReload registra fn para que se ejecute cada vez que config.toml cambia en
+// disco; se usa para que subsistemas como el caché o el rate limiter adopten
+// sus nuevos umbrales sin reiniciar el proceso.
+func OnReload(fn func(*AppConfig)) {
+	subMu.Lock()
+	defer subMu.Unlock()
+	subscribers = append(subscribers, fn)
+}
+
+func notifySubscribers(cfg *AppConfig) {
+	subMu.Lock()
+	fns := make([]func(*AppConfig), len(subscribers))
+	copy(fns, subscribers)
+	subMu.Unlock()
+
+	for _, fn := range fns {
+		fn(cfg)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -13,38 +12,28 @@ import (
 )
 
 var (
-	Client     *mongo.Client
-	Database   *mongo.Database
-	Collection *mongo.Collection
+	Client             *mongo.Client
+	Database           *mongo.Database
+	Collection         *mongo.Collection
+	CategoryCollection *mongo.Collection
 )
 
-// GetMongoURI retorna la URI de MongoDB (configura aquí tu conexión)
-func GetMongoURI() string {
-	// Intentar obtener de variable de entorno primero
-	if uri := os.Getenv("MONGO_URI"); uri != "" {
-		return uri
-	}
-	
-	// REEMPLAZA ESTA LÍNEA CON TU CONEXIÓN DE MONGODB ATLAS
-	return "mongodb+srv://<username>:<password>@cluster0.mongodb.net/?retryWrites=true&w=majority"
-}
-
-// InitDB inicializa la conexión a MongoDB con configuración optimizada
+// InitDB inicializa la conexión a MongoDB a partir de Current().Db.Master
 func InitDB() error {
+	master := Current().Db.Master
+
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	uri := GetMongoURI()
-
 	// Opciones optimizadas del cliente
 	clientOptions := options.Client().
-		ApplyURI(uri).
+		ApplyURI(master.URI).
 		SetMaxPoolSize(100).                   // Pool máximo de conexiones
 		SetMinPoolSize(10).                    // Mantener conexiones mínimas activas
 		SetMaxConnIdleTime(30 * time.Second).  // Limpiar conexiones inactivas
 		SetServerSelectionTimeout(5 * time.Second).
-		SetConnectTimeout(10 * time.Second).
-		SetSocketTimeout(30 * time.Second).
+		SetConnectTimeout(master.ConnectTimeout).
+		SetSocketTimeout(master.ReadTimeout).
 		SetHeartbeatInterval(10 * time.Second).
 		SetRetryWrites(true).                  // Reintentar escrituras fallidas
 		SetRetryReads(true)                    // Reintentar lecturas fallidas
@@ -61,9 +50,9 @@ func InitDB() error {
 	}
 
 	Client = client
-	// Database = client.Database("product_catalog")
-	Database = os.Getenv("MONGO_DB")
+	Database = client.Database(master.Name)
 	Collection = Database.Collection("products")
+	CategoryCollection = Database.Collection("categories")
 
 	log.Println("✅ Connected to MongoDB successfully")
 
@@ -74,6 +63,12 @@ func InitDB() error {
 		log.Println("✅ Database indexes created successfully")
 	}
 
+	if err := createCategoryIndexes(ctx); err != nil {
+		log.Printf("⚠️  Warning: Failed to create some category indexes: %v", err)
+	} else {
+		log.Println("✅ Category indexes created successfully")
+	}
+
 	return nil
 }
 
@@ -137,6 +132,11 @@ func createIndexes(ctx context.Context) error {
 			Keys:    bson.D{{Key: "created_at", Value: -1}},
 			Options: options.Index().SetName("idx_created_at"),
 		},
+		// Índice en tags para intersección de tags en búsquedas
+		{
+			Keys:    bson.D{{Key: "tags", Value: 1}},
+			Options: options.Index().SetName("idx_tags"),
+		},
 	}
 
 	// Crear índices
@@ -144,6 +144,30 @@ func createIndexes(ctx context.Context) error {
 	return err
 }
 
+// createCategoryIndexes crea los índices de la colección de categorías
+func createCategoryIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		// Índice en parent_id para resolver hijos al armar el árbol
+		{
+			Keys:    bson.D{{Key: "parent_id", Value: 1}},
+			Options: options.Index().SetName("idx_parent_id"),
+		},
+		// Índice en status para filtrar el árbol por estado
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}},
+			Options: options.Index().SetName("idx_status"),
+		},
+		// Índice único en slug
+		{
+			Keys:    bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("idx_slug_unique"),
+		},
+	}
+
+	_, err := CategoryCollection.Indexes().CreateMany(ctx, indexes)
+	return err
+}
+
 // Close cierra la conexión a MongoDB de forma segura
 func Close() error {
 	if Client != nil {
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"product-catalog/internal/cache"
+)
+
+type AdminHandler struct {
+	cache *cache.Cache
+}
+
+func NewAdminHandler() *AdminHandler {
+	return &AdminHandler{cache: cache.Get()}
+}
+
+// CacheStats expone el tamaño del caché y sus contadores de hits/misses
+func (h *AdminHandler) CacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"size":   h.cache.Size(),
+		"hits":   h.cache.Hits(),
+		"misses": h.cache.Misses(),
+	})
+}
+
+// FlushCache vacía por completo el caché
+func (h *AdminHandler) FlushCache(c *gin.Context) {
+	h.cache.Clear()
+	c.JSON(http.StatusOK, gin.H{"message": "cache flushed"})
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"product-catalog/internal/cache"
+	"product-catalog/internal/config"
+	"product-catalog/internal/models"
+	"product-catalog/internal/repository"
+)
+
+type CategoryHandler struct {
+	repo  *repository.CategoryRepository
+	cache *cache.Cache
+}
+
+func NewCategoryHandler(repo *repository.CategoryRepository) *CategoryHandler {
+	return &CategoryHandler{
+		repo:  repo,
+		cache: cache.Get(),
+	}
+}
+
+// CreateCategory crea una nueva categoría
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var category models.Category
+
+	if err := c.ShouldBindJSON(&category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.Create(c.Request.Context(), &category); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create category"})
+		return
+	}
+
+	h.cache.DeleteByPrefix("categories:tree:")
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// GetCategory obtiene una categoría por ID
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	category, err := h.repo.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err.Error() == "category not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// ListCategories lista categorías filtradas opcionalmente por status
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	categories, err := h.repo.List(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list categories"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+// GetCategoryTree arma el árbol de categorías a partir de root (opcional) y status
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	root := c.Query("root")
+	status := c.Query("status")
+	cacheKey := "categories:tree:" + root + ":" + status
+
+	var tree []models.CategoryNested
+	if found, err := h.cache.Unmarshal(cacheKey, &tree); err == nil && found {
+		c.JSON(http.StatusOK, gin.H{"tree": tree})
+		return
+	}
+
+	tree, err := h.repo.Tree(c.Request.Context(), root, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build category tree"})
+		return
+	}
+
+	h.cache.Marshal(cacheKey, tree, config.Current().Cache.TTL("categories:tree"))
+
+	c.JSON(http.StatusOK, gin.H{"tree": tree})
+}
+
+// UpdateCategory actualiza una categoría
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	categoryID := c.Param("id")
+
+	var updateData map[string]interface{}
+	if err := c.ShouldBindJSON(&updateData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	update := bson.M{}
+	for key, value := range updateData {
+		if key != "_id" && key != "created_at" {
+			update[key] = value
+		}
+	}
+
+	if len(update) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no valid fields to update"})
+		return
+	}
+
+	if err := h.repo.Update(c.Request.Context(), categoryID, update); err != nil {
+		if err.Error() == "category not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update category"})
+		return
+	}
+
+	h.cache.DeleteByPrefix("categories:tree:")
+
+	c.JSON(http.StatusOK, gin.H{"message": "category updated successfully"})
+}
+
+// DeleteCategory elimina una categoría
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	if err := h.repo.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		if err.Error() == "category not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete category"})
+		return
+	}
+
+	h.cache.DeleteByPrefix("categories:tree:")
+
+	c.JSON(http.StatusOK, gin.H{"message": "category deleted successfully"})
+}
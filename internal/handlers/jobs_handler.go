@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"product-catalog/internal/jobs"
+)
+
+// JobsHandler expone el estado del scheduler de background jobs y permite
+// disparar una corrida manual, montado detrás de middleware.AdminAuth.
+type JobsHandler struct {
+	scheduler *jobs.Scheduler
+}
+
+func NewJobsHandler(scheduler *jobs.Scheduler) *JobsHandler {
+	return &JobsHandler{scheduler: scheduler}
+}
+
+// ListJobs devuelve el estado de todos los jobs registrados
+func (h *JobsHandler) ListJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"jobs": h.scheduler.Status()})
+}
+
+// RunJob dispara una corrida manual del job indicado, fuera de su schedule
+func (h *JobsHandler) RunJob(c *gin.Context) {
+	name := c.Param("name")
+
+	if err := h.scheduler.TriggerNow(name); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "job triggered", "job": name})
+}
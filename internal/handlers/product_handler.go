@@ -1,17 +1,24 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"sort"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 
 	"product-catalog/internal/cache"
+	"product-catalog/internal/config"
+	"product-catalog/internal/jobs"
 	"product-catalog/internal/models"
 	"product-catalog/internal/repository"
+	"product-catalog/internal/validation"
 )
 
 type ProductHandler struct {
@@ -29,12 +36,17 @@ func NewProductHandler(repo *repository.ProductRepository) *ProductHandler {
 // CreateProduct crea un nuevo producto
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	var product models.Product
-	
+
 	if err := c.ShouldBindJSON(&product); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if err := validation.Validate.Struct(&product); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"errors": validation.GetValidationErrors(err)})
+		return
+	}
+
 	if err := h.repo.Create(c.Request.Context(), &product); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create product"})
 		return
@@ -68,14 +80,21 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		return
 	}
 
-	// Guardar en caché por 5 minutos
-	h.cache.Set(cacheKey, product, 5*time.Minute)
+	// Guardar en caché con el TTL configurado para este endpoint
+	h.cache.Set(cacheKey, product, config.Current().Cache.TTL("product"))
 
 	c.JSON(http.StatusOK, product)
 }
 
-// ListProducts lista productos con paginación y filtros (con caché)
+// ListProducts lista productos con paginación y filtros (con caché).
+// Soporta dos modos de paginación: page/page_size (skip/limit, por compatibilidad)
+// y page_token (keyset), preferido para colecciones grandes o de escritura frecuente.
 func (h *ProductHandler) ListProducts(c *gin.Context) {
+	if c.Query("page_token") != "" || c.Query("cursor") == "true" {
+		h.listProductsCursor(c)
+		return
+	}
+
 	// Parsear parámetros
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
@@ -88,6 +107,12 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 	cacheKey := fmt.Sprintf("products:list:%d:%d:%s:%s:%s:%v",
 		page, pageSize, category, sortBy, sortOrder, summary)
 
+	// Trackear popularidad para que jobs.CacheWarmerJob sepa qué precargar
+	jobs.RecordListQuery(cacheKey, jobs.ListQueryParams{
+		Page: page, PageSize: pageSize, Category: category,
+		SortBy: sortBy, SortOrder: sortOrder, Summary: summary,
+	})
+
 	// Intentar obtener del caché
 	type CachedResponse struct {
 		Products []*models.Product `json:"products"`
@@ -116,12 +141,236 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 		PageSize: pageSize,
 	}
 
-	// Guardar en caché por 2 minutos
-	h.cache.Marshal(cacheKey, response, 2*time.Minute)
+	// Guardar en caché con el TTL configurado para este endpoint
+	h.cache.Marshal(cacheKey, response, config.Current().Cache.TTL("products:list"))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// listProductsCursor implementa el modo de paginación por page_token (keyset).
+// A diferencia de page/page_size, el resultado no se cachea porque cada token
+// referencia una posición concreta del cursor, no una página estable.
+func (h *ProductHandler) listProductsCursor(c *gin.Context) {
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	category := c.Query("category")
+	sortBy := c.DefaultQuery("sort_by", "created_at")
+	sortOrder := c.DefaultQuery("sort_order", "desc")
+	pageToken := c.Query("page_token")
+
+	products, nextToken, err := h.repo.FindAllCursor(c.Request.Context(), pageSize, category, sortBy, sortOrder, pageToken)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"products":  products,
+		"page_size": pageSize,
+	}
+	if nextToken != "" {
+		response["next_page_token"] = nextToken
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// defaultPriceBoundaries son los límites del histograma de precios usados por
+// SearchProducts cuando el caller no pasa price_boundaries, iguales a los de GetFacets.
+var defaultPriceBoundaries = []int64{0, 1000, 5000, 10000, 25000, 50000, 100000}
+
+// sortedJoin normaliza una lista de valores de query multi-valor (category=, tags=)
+// para que el orden en que el caller los envió no produzca claves de caché distintas.
+func sortedJoin(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// boolPtrKey formatea un *bool para una clave de caché por su valor, no por su
+// dirección de memoria (que %v produciría y cambia en cada petición, tirando el hit rate a cero).
+func boolPtrKey(b *bool) string {
+	if b == nil {
+		return "-"
+	}
+	return strconv.FormatBool(*b)
+}
+
+// SearchProducts busca productos por texto (índice $text, con fallback a $regex) y
+// combina el resultado con facets de categoría/precio sobre la misma vista filtrada.
+// Acepta múltiples category= y tags= (intersección), rango de precio, disponibilidad
+// de stock y pagina igual que ListProducts, cacheando bajo el mismo prefijo
+// "products:list:" para que las invalidaciones de escritura también lo cubran.
+func (h *ProductHandler) SearchProducts(c *gin.Context) {
+	q := c.Query("q")
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	categories := c.QueryArray("category")
+	tags := c.QueryArray("tags")
+	minPrice, _ := strconv.ParseInt(c.Query("min_price"), 10, 64)
+	maxPrice, _ := strconv.ParseInt(c.Query("max_price"), 10, 64)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	sortBy := c.Query("sort_by")
+	sortOrder := c.DefaultQuery("sort_order", "desc")
+	forceRegex := c.Query("search") == "regex"
+
+	if sortBy != "" && sortBy != "score" && !repository.IsSearchSortField(sortBy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown sort field %q", sortBy)})
+		return
+	}
+	if sortBy == "score" && !repository.IsTextSearchQuery(q, forceRegex) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort_by=score requires a text search query"})
+		return
+	}
+
+	var active *bool
+	if a := c.Query("active"); a != "" {
+		v := a == "true"
+		active = &v
+	}
+	var inStock *bool
+	if s := c.Query("in_stock"); s != "" {
+		v := s == "true"
+		inStock = &v
+	}
+
+	filter := bson.M{"is_deleted": false}
+	if len(categories) == 1 {
+		filter["category"] = categories[0]
+	} else if len(categories) > 1 {
+		filter["category"] = bson.M{"$in": categories}
+	}
+	if active != nil {
+		filter["is_active"] = *active
+	}
+	if inStock != nil {
+		if *inStock {
+			filter["stock"] = bson.M{"$gt": 0}
+		} else {
+			filter["stock"] = bson.M{"$lte": 0}
+		}
+	}
+	if len(tags) > 0 {
+		filter["tags"] = bson.M{"$all": tags}
+	}
+	priceFilter := bson.M{}
+	if minPrice > 0 {
+		priceFilter["$gte"] = minPrice
+	}
+	if maxPrice > 0 {
+		priceFilter["$lte"] = maxPrice
+	}
+	if len(priceFilter) > 0 {
+		filter["price_cents"] = priceFilter
+	}
+
+	cacheKey := fmt.Sprintf("products:list:search:%s:%s:%s:%d:%d:%d:%d:%s:%s:%s:%s:%v",
+		q, sortedJoin(categories), sortedJoin(tags), minPrice, maxPrice, page, pageSize,
+		boolPtrKey(active), boolPtrKey(inStock), sortBy, sortOrder, forceRegex)
+
+	type searchResponse struct {
+		Query    string             `json:"query"`
+		Page     int                `json:"page"`
+		PageSize int                `json:"page_size"`
+		Total    int64              `json:"total"`
+		Products []*models.Product  `json:"products"`
+		Facets   *repository.Facets `json:"facets"`
+	}
+
+	var response searchResponse
+	if found, err := h.cache.Unmarshal(cacheKey, &response); err == nil && found {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	products, total, facets, err := h.repo.Search(c.Request.Context(), filter, q, sortBy, sortOrder, page, pageSize, forceRegex, defaultPriceBoundaries)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "search failed"})
+		return
+	}
+
+	response = searchResponse{
+		Query:    q,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+		Products: products,
+		Facets:   facets,
+	}
+
+	h.cache.Marshal(cacheKey, response, config.Current().Cache.TTL("products:list:search"))
 
 	c.JSON(http.StatusOK, response)
 }
 
+// GetFacets calcula categorías, histograma de precios, disponibilidad de stock y
+// (opcionalmente) los valores más frecuentes de un atributo, respetando los
+// filtros category, min_price/max_price, active y q (por $regex). ListProducts
+// sólo filtra por category, así que con los demás filtros los facets no
+// coinciden con ese listado.
+func (h *ProductHandler) GetFacets(c *gin.Context) {
+	filter := bson.M{"is_deleted": false}
+
+	if cat := c.Query("category"); cat != "" {
+		filter["category"] = cat
+	}
+	if active := c.Query("active"); active != "" {
+		filter["is_active"] = active == "true"
+	}
+	if q := c.Query("q"); q != "" {
+		filter["$or"] = []bson.M{
+			{"name": bson.M{"$regex": q, "$options": "i"}},
+			{"description": bson.M{"$regex": q, "$options": "i"}},
+		}
+	}
+	priceFilter := bson.M{}
+	if minPrice, err := strconv.ParseInt(c.Query("min_price"), 10, 64); err == nil && minPrice > 0 {
+		priceFilter["$gte"] = minPrice
+	}
+	if maxPrice, err := strconv.ParseInt(c.Query("max_price"), 10, 64); err == nil && maxPrice > 0 {
+		priceFilter["$lte"] = maxPrice
+	}
+	if len(priceFilter) > 0 {
+		filter["price_cents"] = priceFilter
+	}
+
+	boundaries := []int64{0, 1000, 5000, 10000, 25000, 50000, 100000}
+	if raw := c.Query("price_boundaries"); raw != "" {
+		parsed := make([]int64, 0)
+		for _, part := range strings.Split(raw, ",") {
+			v, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid price_boundaries"})
+				return
+			}
+			parsed = append(parsed, v)
+		}
+		if len(parsed) >= 2 {
+			boundaries = parsed
+		}
+	}
+
+	attribute := c.Query("attribute")
+	topN, _ := strconv.Atoi(c.DefaultQuery("top", "10"))
+
+	facets, err := h.repo.Facets(c.Request.Context(), filter, boundaries, attribute, topN)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute facets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, facets)
+}
+
 // UpdateProduct actualiza un producto
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	productID := c.Param("id")
@@ -135,7 +384,7 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	// Convertir a bson.M
 	update := bson.M{}
 	for key, value := range updateData {
-		if key != "_id" && key != "created_at" && key != "is_deleted" {
+		if key != "_id" && key != "created_at" && key != "is_deleted" && key != "version" && key != "expected_version" {
 			update[key] = value
 		}
 	}
@@ -145,7 +394,23 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.Update(c.Request.Context(), productID, update); err != nil {
+	if verrs := validatePartialProduct(updateData); len(verrs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"errors": verrs})
+		return
+	}
+
+	expectedVersion, err := parseExpectedVersion(c, updateData)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.Update(c.Request.Context(), productID, update, expectedVersion); err != nil {
+		var conflict *repository.VersionConflictError
+		if errors.As(err, &conflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "version conflict", "current_version": conflict.CurrentVersion})
+			return
+		}
 		if err.Error() == "product not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "product not found"})
 			return
@@ -161,6 +426,85 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "product updated successfully"})
 }
 
+// parseExpectedVersion obtiene la versión esperada del header If-Match o, si no está
+// presente, del campo expected_version del body. Devuelve nil si el caller no pidió
+// control de concurrencia optimista.
+func parseExpectedVersion(c *gin.Context, updateData map[string]interface{}) (*int64, error) {
+	if header := c.GetHeader("If-Match"); header != "" {
+		version, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid If-Match header: %s", header)
+		}
+		return &version, nil
+	}
+
+	raw, ok := updateData["expected_version"]
+	if !ok {
+		return nil, nil
+	}
+
+	version, ok := raw.(float64)
+	if !ok {
+		return nil, fmt.Errorf("expected_version must be a number")
+	}
+	v := int64(version)
+	return &v, nil
+}
+
+// validatePartialProduct valida sólo las reglas de los campos presentes en updateData,
+// preservando la semántica de PATCH: un campo ausente no debe fallar por required=false
+// del resto del struct.
+func validatePartialProduct(updateData map[string]interface{}) []validation.FieldError {
+	keys := make(map[string]bool, len(updateData))
+	for key := range updateData {
+		keys[key] = true
+	}
+
+	fields := validation.FieldsForKeys(reflect.TypeOf(models.Product{}), keys)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(updateData)
+	if err != nil {
+		return nil
+	}
+	var product models.Product
+	if err := json.Unmarshal(raw, &product); err != nil {
+		return nil
+	}
+
+	if err := validation.Validate.StructPartial(&product, fields...); err != nil {
+		return validation.GetValidationErrors(err)
+	}
+	return nil
+}
+
+// BulkWrite ejecuta un lote de inserciones/actualizaciones/eliminaciones en una sola petición
+func (h *ProductHandler) BulkWrite(c *gin.Context) {
+	var ops []repository.BulkOperation
+	if err := c.ShouldBindJSON(&ops); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(ops) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at least one operation is required"})
+		return
+	}
+
+	ordered := c.DefaultQuery("ordered", "true") == "true"
+
+	results, err := h.repo.BulkWrite(c.Request.Context(), ops, ordered)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "bulk write failed", "results": results})
+		return
+	}
+
+	h.cache.DeleteByPrefix("products:list:")
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
 // DeleteProduct elimina (soft delete) un producto
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	productID := c.Param("id")
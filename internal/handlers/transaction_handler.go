@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"product-catalog/internal/service"
+)
+
+type TransactionHandler struct {
+	service *service.ProductService
+}
+
+func NewTransactionHandler(svc *service.ProductService) *TransactionHandler {
+	return &TransactionHandler{service: svc}
+}
+
+// AdjustStock aplica ajustes de stock a varios SKUs de forma atómica
+func (h *TransactionHandler) AdjustStock(c *gin.Context) {
+	var body struct {
+		Items []service.StockAdjustment `json:"items" binding:"required,dive"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(body.Items) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "items is required"})
+		return
+	}
+
+	if err := h.service.AdjustStock(c.Request.Context(), body.Items); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "stock adjusted"})
+}
+
+// RenameCategory renombra (campo Name) la categoría identificada por su slug y
+// reescribe los productos asociados a su ID
+func (h *TransactionHandler) RenameCategory(c *gin.Context) {
+	slug := c.Param("name")
+
+	var body struct {
+		NewName string `json:"new_name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.RenameCategory(c.Request.Context(), slug, body.NewName); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category renamed"})
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"product-catalog/internal/repository"
+)
+
+// PurgeJob elimina definitivamente los productos con is_deleted=true cuya
+// última actualización supera retention, liberando el soft-delete acumulado
+// por SoftDelete/BulkWrite una vez pasó la ventana de retención.
+type PurgeJob struct {
+	repo      *repository.ProductRepository
+	retention time.Duration
+}
+
+func NewPurgeJob(repo *repository.ProductRepository, retention time.Duration) *PurgeJob {
+	return &PurgeJob{repo: repo, retention: retention}
+}
+
+func (j *PurgeJob) Name() string { return "purge_deleted" }
+
+func (j *PurgeJob) Run(ctx context.Context) (string, error) {
+	deleted, err := j.repo.PurgeDeleted(ctx, j.retention)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("purged %d documents older than %s", deleted, j.retention), nil
+}
@@ -0,0 +1,142 @@
+// Package jobs implementa un scheduler de background jobs con expresiones
+// cron-style, compartiendo ProductRepository y cache.Cache con el servidor HTTP.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Job es una tarea de background registrable en el Scheduler. Run devuelve un
+// resumen corto para exponer en /v1/admin/jobs (p. ej. "purged 12 documents").
+type Job interface {
+	Name() string
+	Run(ctx context.Context) (string, error)
+}
+
+// JobStatus resume el estado de un job registrado para el endpoint de listado
+type JobStatus struct {
+	Name       string    `json:"name"`
+	Schedule   string    `json:"schedule"`
+	Running    bool      `json:"running"`
+	RunCount   int64     `json:"run_count"`
+	LastRun    time.Time `json:"last_run,omitempty"`
+	LastResult string    `json:"last_result,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+type registeredJob struct {
+	job    Job
+	status JobStatus
+}
+
+// Scheduler ejecuta jobs según expresiones cron-style (incluyendo descriptores
+// "@every 5m") y honra el apagado ordenado de main.go: Stop deja de agendar
+// nuevas ejecuciones por cron y espera hasta drainTimeout a que las corridas
+// agendadas activas terminen. Las corridas lanzadas con TriggerNow no pasan por
+// cron: Stop no las espera y TriggerNow las sigue aceptando después de Stop.
+type Scheduler struct {
+	cron *cron.Cron
+	mu   sync.Mutex
+	jobs map[string]*registeredJob
+}
+
+// NewScheduler crea un scheduler vacío, sin arrancar
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		cron: cron.New(),
+		jobs: make(map[string]*registeredJob),
+	}
+}
+
+// Register agenda job según schedule (p. ej. "@every 5m", "0 */1 * * *")
+func (s *Scheduler) Register(schedule string, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name()]; exists {
+		return fmt.Errorf("job %q already registered", job.Name())
+	}
+
+	rj := &registeredJob{job: job, status: JobStatus{Name: job.Name(), Schedule: schedule}}
+	if _, err := s.cron.AddFunc(schedule, func() { s.run(rj) }); err != nil {
+		return fmt.Errorf("invalid schedule %q for job %q: %w", schedule, job.Name(), err)
+	}
+	s.jobs[job.Name()] = rj
+	return nil
+}
+
+// Start arranca el loop del scheduler en background
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop deja de agendar nuevas ejecuciones y espera hasta drainTimeout a que las
+// que cron lanzó y están corriendo terminen, para que main.go pueda apagar sin
+// cortarlas a la mitad. Las corridas de TriggerNow no se esperan.
+func (s *Scheduler) Stop(drainTimeout time.Duration) {
+	drained := s.cron.Stop()
+	select {
+	case <-drained.Done():
+	case <-time.After(drainTimeout):
+		log.Println("⚠️  jobs: drain timeout exceeded, some jobs may still be running")
+	}
+}
+
+// TriggerNow ejecuta name inmediatamente en background, fuera de su schedule
+func (s *Scheduler) TriggerNow(name string) error {
+	s.mu.Lock()
+	rj, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown job %q", name)
+	}
+
+	go s.run(rj)
+	return nil
+}
+
+// Status devuelve el estado de todos los jobs registrados, ordenados por nombre
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, rj := range s.jobs {
+		statuses = append(statuses, rj.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+func (s *Scheduler) run(rj *registeredJob) {
+	s.mu.Lock()
+	rj.status.Running = true
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	result, err := rj.job.Run(ctx)
+
+	s.mu.Lock()
+	rj.status.Running = false
+	rj.status.RunCount++
+	rj.status.LastRun = time.Now()
+	if err != nil {
+		rj.status.LastError = err.Error()
+	} else {
+		rj.status.LastError = ""
+		rj.status.LastResult = result
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		log.Printf("⚠️  job %q failed: %v", rj.job.Name(), err)
+	}
+}
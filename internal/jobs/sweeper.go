@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+
+	"product-catalog/internal/cache"
+)
+
+// SweepJob compacta las entradas de caché ya expiradas y deja registrado el
+// hit/miss ratio acumulado, útil para detectar TTLs mal calibrados sin tener
+// que consultar /v1/admin/cache/stats manualmente.
+type SweepJob struct {
+	cache *cache.Cache
+}
+
+func NewSweepJob(c *cache.Cache) *SweepJob {
+	return &SweepJob{cache: c}
+}
+
+func (j *SweepJob) Name() string { return "cache_sweep" }
+
+func (j *SweepJob) Run(ctx context.Context) (string, error) {
+	removed := j.cache.Sweep()
+	hits, misses := j.cache.Hits(), j.cache.Misses()
+
+	ratio := 0.0
+	if total := hits + misses; total > 0 {
+		ratio = float64(hits) / float64(total)
+	}
+
+	return fmt.Sprintf("swept %d expired entries, hit ratio %.2f (%d hits / %d misses)", removed, ratio, hits, misses), nil
+}
@@ -0,0 +1,116 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"product-catalog/internal/cache"
+	"product-catalog/internal/models"
+	"product-catalog/internal/repository"
+)
+
+// ListQueryParams son los parámetros de una consulta a ListProducts, suficientes
+// para volver a ejecutarla y regenerar la entrada de caché correspondiente.
+type ListQueryParams struct {
+	Page      int
+	PageSize  int
+	Category  string
+	SortBy    string
+	SortOrder string
+	Summary   bool
+}
+
+type queryHit struct {
+	params ListQueryParams
+	count  int64
+}
+
+var (
+	trackerMu sync.Mutex
+	tracker   = make(map[string]*queryHit)
+)
+
+// RecordListQuery incrementa el contador de popularidad de cacheKey, para que
+// CacheWarmerJob sepa qué páginas de listado precargar en su próxima corrida.
+func RecordListQuery(cacheKey string, params ListQueryParams) {
+	trackerMu.Lock()
+	defer trackerMu.Unlock()
+
+	hit, ok := tracker[cacheKey]
+	if !ok {
+		hit = &queryHit{params: params}
+		tracker[cacheKey] = hit
+	}
+	hit.count++
+}
+
+// topQueries devuelve las n claves más consultadas, tal cual las trackeó RecordListQuery
+func topQueries(n int) map[string]ListQueryParams {
+	trackerMu.Lock()
+	defer trackerMu.Unlock()
+
+	keys := make([]string, 0, len(tracker))
+	for k := range tracker {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return tracker[keys[i]].count > tracker[keys[j]].count })
+
+	if n > len(keys) {
+		n = len(keys)
+	}
+	top := make(map[string]ListQueryParams, n)
+	for _, k := range keys[:n] {
+		top[k] = tracker[k].params
+	}
+	return top
+}
+
+// warmedListResponse replica la forma de CachedResponse en ProductHandler.ListProducts
+// para que las entradas que este job precarga sean indistinguibles de las que
+// deja un cache miss normal.
+type warmedListResponse struct {
+	Products []*models.Product `json:"products"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+}
+
+// CacheWarmerJob repuebla periódicamente las entradas de products:list: más
+// consultadas (según RecordListQuery) antes de que expiren, para que los
+// clientes más frecuentes no paguen el costo del cache miss.
+type CacheWarmerJob struct {
+	repo  *repository.ProductRepository
+	cache *cache.Cache
+	topN  int
+	ttl   time.Duration
+}
+
+func NewCacheWarmerJob(repo *repository.ProductRepository, c *cache.Cache, topN int, ttl time.Duration) *CacheWarmerJob {
+	return &CacheWarmerJob{repo: repo, cache: c, topN: topN, ttl: ttl}
+}
+
+func (j *CacheWarmerJob) Name() string { return "cache_warmer" }
+
+func (j *CacheWarmerJob) Run(ctx context.Context) (string, error) {
+	top := topQueries(j.topN)
+
+	warmed := 0
+	for key, p := range top {
+		products, total, err := j.repo.FindAll(ctx, p.Page, p.PageSize, p.Category, p.SortBy, p.SortOrder, p.Summary)
+		if err != nil {
+			continue
+		}
+		j.cache.Marshal(key, warmedListResponse{
+			Products: products,
+			Total:    total,
+			Page:     p.Page,
+			PageSize: p.PageSize,
+		}, j.ttl)
+		warmed++
+	}
+
+	return fmt.Sprintf("warmed %d/%d tracked queries", warmed, len(top)), nil
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth es un stub de autenticación para rutas administrativas: exige la
+// cabecera X-Admin-Token y la compara contra el valor de config.toml. Sirve de
+// placeholder hasta que se integre un proveedor de auth real (OAuth, API keys, etc).
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" || c.GetHeader("X-Admin-Token") != token {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		c.Next()
+	}
+}
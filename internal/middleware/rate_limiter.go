@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// visitorIdleWindow es cuánto puede pasar sin que una IP haga una petición antes
+// de que su visitor se purgue del mapa global
+const visitorIdleWindow = 10 * time.Minute
+
+// visitor guarda el limitador de una IP y se purga si queda inactivo más de
+// visitorIdleWindow (ver sweepIdleVisitors)
+type visitor struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+var (
+	visitors          = make(map[string]*visitor)
+	mu                sync.Mutex
+	requestsPerMinute atomic.Int64
+)
+
+func init() {
+	requestsPerMinute.Store(100)
+	go sweepIdleVisitors()
+}
+
+// sweepIdleVisitors purga periódicamente las IPs sin peticiones recientes para
+// que el mapa global no crezca sin límite en un endpoint público
+func sweepIdleVisitors() {
+	ticker := time.NewTicker(visitorIdleWindow)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		cutoff := time.Now().Add(-visitorIdleWindow)
+		mu.Lock()
+		for ip, v := range visitors {
+			if v.lastSeen.Before(cutoff) {
+				delete(visitors, ip)
+			}
+		}
+		mu.Unlock()
+	}
+}
+
+// SetRateLimit actualiza el umbral de peticiones por minuto por IP; los visitantes
+// ya registrados lo adoptan en su siguiente petición sin perder el estado de su
+// propio limitador, para que un cambio de configuración en caliente no los resetee.
+func SetRateLimit(perMinute int) {
+	requestsPerMinute.Store(int64(perMinute))
+}
+
+// RateLimiter limita el número de peticiones por IP al umbral vigente, leído de
+// un entero atómico actualizable en caliente con SetRateLimit.
+func RateLimiter() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+		perMinute := requestsPerMinute.Load()
+		limit := rate.Limit(float64(perMinute) / 60.0)
+		burst := int(perMinute)
+
+		mu.Lock()
+		v, ok := visitors[ip]
+		if !ok {
+			v = &visitor{limiter: rate.NewLimiter(limit, burst)}
+			visitors[ip] = v
+		} else {
+			v.limiter.SetLimit(limit)
+			v.limiter.SetBurst(burst)
+		}
+		v.lastSeen = time.Now()
+		mu.Unlock()
+
+		if !v.limiter.Allow() {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+
+		c.Next()
+	}
+}
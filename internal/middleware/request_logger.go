@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"product-catalog/internal/config"
+)
+
+// RequestLoggerConfig configura dónde y cómo se escriben los logs de acceso
+type RequestLoggerConfig struct {
+	FilePath   string // ruta del archivo de log, ej. storage/logs/app.log
+	JSON       bool   // si true, cada línea es un objeto JSON; si no, texto plano
+	MaxSizeMB  int    // tamaño máximo del archivo antes de rotar, en MB
+	MaxBackups int    // número de archivos rotados a conservar
+	MaxAgeDays int    // días máximos antes de descartar un archivo rotado
+}
+
+// NewRequestLoggerConfig traduce la sección [Log] de config.AppConfig a la
+// configuración que necesita el middleware.
+func NewRequestLoggerConfig(logCfg config.LogConfig) RequestLoggerConfig {
+	return RequestLoggerConfig{
+		FilePath:   logCfg.FilePath,
+		JSON:       logCfg.JSON,
+		MaxSizeMB:  logCfg.MaxSizeMB,
+		MaxBackups: logCfg.MaxBackups,
+		MaxAgeDays: logCfg.MaxAgeDays,
+	}
+}
+
+// requestLogEntry es la línea estructurada escrita por petición
+type requestLogEntry struct {
+	Timestamp string `json:"timestamp"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Status    int    `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	ClientIP  string `json:"client_ip"`
+	UserAgent string `json:"user_agent"`
+	RequestID string `json:"request_id"`
+	BytesOut  int    `json:"bytes_out"`
+}
+
+// RequestLogger escribe una línea estructurada por petición (timestamp, método, path,
+// status, latencia, IP, user-agent, request ID y bytes de salida) al archivo indicado
+// en cfg.FilePath, rotado por tamaño/backups/antigüedad al estilo lumberjack para que
+// no crezca sin límite en un servidor de larga duración.
+func RequestLogger(cfg RequestLoggerConfig) gin.HandlerFunc {
+	writer := newLogWriter(cfg)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		entry := requestLogEntry{
+			Timestamp: start.UTC().Format(time.RFC3339),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Status:    c.Writer.Status(),
+			LatencyMS: time.Since(start).Milliseconds(),
+			ClientIP:  c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			RequestID: c.GetString("request_id"),
+			BytesOut:  c.Writer.Size(),
+		}
+
+		writeLogEntry(writer, cfg.JSON, entry)
+	}
+}
+
+// newLogWriter abre (o crea) cfg.FilePath en modo append y lo envuelve en un
+// lumberjack.Logger que rota el archivo por tamaño, backups y antigüedad.
+func newLogWriter(cfg RequestLoggerConfig) io.Writer {
+	if err := os.MkdirAll(filepath.Dir(cfg.FilePath), 0755); err != nil {
+		return os.Stderr
+	}
+
+	// Crear el archivo si no existe para que lumberjack encuentre algo que abrir
+	// en modo append; lumberjack se encarga de la rotación a partir de aquí.
+	f, err := os.OpenFile(cfg.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return os.Stderr
+	}
+	f.Close()
+
+	return &lumberjack.Logger{
+		Filename:   cfg.FilePath,
+		MaxSize:    cfg.MaxSizeMB,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAgeDays,
+		Compress:   true,
+	}
+}
+
+func writeLogEntry(w io.Writer, asJSON bool, entry requestLogEntry) {
+	if asJSON {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return
+		}
+		w.Write(append(data, '\n'))
+		return
+	}
+
+	line := fmt.Sprintf("%s %s %s %d %dms ip=%s request_id=%s bytes=%d ua=%q\n",
+		entry.Timestamp, entry.Method, entry.Path, entry.Status, entry.LatencyMS,
+		entry.ClientIP, entry.RequestID, entry.BytesOut, entry.UserAgent)
+	w.Write([]byte(line))
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Category representa una categoría del catálogo, con soporte para jerarquías
+// vía ParentID auto-referencial.
+type Category struct {
+	ID        primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Name      string              `json:"name" bson:"name" binding:"required"`
+	Slug      string              `json:"slug" bson:"slug" binding:"required"`
+	ParentID  *primitive.ObjectID `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	Sorter    int                 `json:"sorter" bson:"sorter"`
+	Status    string              `json:"status" bson:"status"`
+	CreatedAt time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// CategoryUpdate representa los campos actualizables de una categoría
+type CategoryUpdate struct {
+	Name     *string             `json:"name,omitempty"`
+	Slug     *string             `json:"slug,omitempty"`
+	ParentID *primitive.ObjectID `json:"parent_id,omitempty"`
+	Sorter   *int                `json:"sorter,omitempty"`
+	Status   *string             `json:"status,omitempty"`
+}
+
+// CategoryNested es un nodo de árbol de categorías con sus hijos ya resueltos
+type CategoryNested struct {
+	Category
+	Children []CategoryNested `json:"children"`
+}
@@ -9,17 +9,19 @@ import (
 // Product representa un producto en el catálogo
 type Product struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	SKU         string             `json:"sku" bson:"sku" binding:"required"`
-	Name        string             `json:"name" bson:"name" binding:"required"`
-	Description string             `json:"description,omitempty" bson:"description,omitempty"`
-	Category    string             `json:"category" bson:"category" binding:"required"`
-	PriceCents  int                `json:"price_cents" bson:"price_cents" binding:"required"`
-	Currency    string             `json:"currency" bson:"currency" binding:"required"`
-	Stock       int                `json:"stock" bson:"stock"`
+	SKU         string             `json:"sku" bson:"sku" binding:"required" validate:"required,sku"`
+	Name        string             `json:"name" bson:"name" binding:"required" validate:"required,min=2,max=200"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty" validate:"max=2000"`
+	Category    string             `json:"category" bson:"category" binding:"required" validate:"required,category_exists"` // ID de una Category existente
+	PriceCents  int                `json:"price_cents" bson:"price_cents" binding:"required" validate:"gte=0"`
+	Currency    string             `json:"currency" bson:"currency" binding:"required" validate:"required,len=3"`
+	Stock       int                `json:"stock" bson:"stock" validate:"gte=0"`
 	Images      []string           `json:"images,omitempty" bson:"images,omitempty"`
+	Tags        []string           `json:"tags,omitempty" bson:"tags,omitempty"`
 	Attributes  map[string]string  `json:"attributes,omitempty" bson:"attributes,omitempty"`
 	IsActive    bool               `json:"is_active" bson:"is_active"`
 	IsDeleted   bool               `json:"-" bson:"is_deleted"`
+	Version     int64              `json:"version" bson:"version"`
 	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
 }
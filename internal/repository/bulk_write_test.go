@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestBulkFilter(t *testing.T) {
+	validID := primitive.NewObjectID().Hex()
+
+	if _, err := bulkFilter(BulkOperation{ID: validID}); err != nil {
+		t.Errorf("bulkFilter with valid id returned error: %v", err)
+	}
+	if _, err := bulkFilter(BulkOperation{ID: "not-an-object-id"}); err == nil {
+		t.Error("expected error for invalid id, got nil")
+	}
+	if _, err := bulkFilter(BulkOperation{SKU: "ABC-123"}); err != nil {
+		t.Errorf("bulkFilter with sku returned error: %v", err)
+	}
+	if _, err := bulkFilter(BulkOperation{}); err == nil {
+		t.Error("expected error when neither id nor sku is set, got nil")
+	}
+}
+
+func TestBuildBulkWriteModel(t *testing.T) {
+	validID := primitive.NewObjectID().Hex()
+
+	t.Run("insert", func(t *testing.T) {
+		model, id, err := buildBulkWriteModel(BulkOperation{Op: "insert", Data: map[string]interface{}{"sku": "ABC-1"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id == "" {
+			t.Error("expected a generated id for insert")
+		}
+		if _, ok := model.(*mongo.InsertOneModel); !ok {
+			t.Errorf("model is %T, want *mongo.InsertOneModel", model)
+		}
+	})
+
+	t.Run("update requires data", func(t *testing.T) {
+		if _, _, err := buildBulkWriteModel(BulkOperation{Op: "update", ID: validID}); err == nil {
+			t.Error("expected error when update has no data, got nil")
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		model, id, err := buildBulkWriteModel(BulkOperation{
+			Op: "update", ID: validID, Data: map[string]interface{}{"stock": 5},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != validID {
+			t.Errorf("id = %q, want %q", id, validID)
+		}
+		if _, ok := model.(*mongo.UpdateOneModel); !ok {
+			t.Errorf("model is %T, want *mongo.UpdateOneModel", model)
+		}
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		model, _, err := buildBulkWriteModel(BulkOperation{
+			Op: "upsert", SKU: "ABC-1", Data: map[string]interface{}{"stock": 5},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := model.(*mongo.UpdateOneModel); !ok {
+			t.Errorf("model is %T, want *mongo.UpdateOneModel", model)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		model, id, err := buildBulkWriteModel(BulkOperation{Op: "delete", ID: validID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id != validID {
+			t.Errorf("id = %q, want %q", id, validID)
+		}
+		if _, ok := model.(*mongo.UpdateOneModel); !ok {
+			t.Errorf("model is %T, want *mongo.UpdateOneModel", model)
+		}
+	})
+
+	t.Run("unknown op", func(t *testing.T) {
+		if _, _, err := buildBulkWriteModel(BulkOperation{Op: "destroy", ID: validID}); err == nil {
+			t.Error("expected error for unknown op, got nil")
+		}
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		if _, _, err := buildBulkWriteModel(BulkOperation{Op: "delete", ID: "not-an-object-id"}); err == nil {
+			t.Error("expected error for invalid id, got nil")
+		}
+	})
+}
+
+// TestBulkWriteOrderedPreflightAbort covers the pre-flight validation path that
+// never reaches r.collection (so it needs no mongo connection): when every op
+// fails buildBulkWriteModel validation and ordered=true, only the first failing
+// op should be marked "error", and the rest must report their real index as "skipped"
+// rather than "" at index 0.
+func TestBulkWriteOrderedPreflightAbort(t *testing.T) {
+	repo := &ProductRepository{}
+	ops := []BulkOperation{
+		{Op: "delete", ID: "not-an-object-id"},
+		{Op: "delete", ID: "also-not-an-object-id"},
+		{Op: "delete", ID: "still-not-an-object-id"},
+	}
+
+	results, err := repo.BulkWrite(context.Background(), ops, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != len(ops) {
+		t.Fatalf("got %d results, want %d", len(results), len(ops))
+	}
+
+	for i, r := range results {
+		if r.Index != i {
+			t.Errorf("results[%d].Index = %d, want %d", i, r.Index, i)
+		}
+	}
+	if results[0].Status != "error" {
+		t.Errorf("results[0].Status = %q, want %q", results[0].Status, "error")
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Status != "skipped" {
+			t.Errorf("results[%d].Status = %q, want %q", i, results[i].Status, "skipped")
+		}
+	}
+}
+
+func TestBulkWriteUnorderedPreflightContinues(t *testing.T) {
+	repo := &ProductRepository{}
+	ops := []BulkOperation{
+		{Op: "delete", ID: "not-an-object-id"},
+		{Op: "delete", ID: "also-not-an-object-id"},
+	}
+
+	results, err := repo.BulkWrite(context.Background(), ops, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, r := range results {
+		if r.Index != i {
+			t.Errorf("results[%d].Index = %d, want %d", i, r.Index, i)
+		}
+		if r.Status != "error" {
+			t.Errorf("results[%d].Status = %q, want %q", i, r.Status, "error")
+		}
+	}
+}
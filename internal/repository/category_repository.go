@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"product-catalog/internal/models"
+)
+
+type CategoryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCategoryRepository(collection *mongo.Collection) *CategoryRepository {
+	return &CategoryRepository{
+		collection: collection,
+	}
+}
+
+// Create crea una nueva categoría
+func (r *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	category.ID = primitive.NewObjectID()
+	if category.Status == "" {
+		category.Status = "active"
+	}
+	category.CreatedAt = time.Now()
+	category.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, category)
+	return err
+}
+
+// FindByID obtiene una categoría por ID
+func (r *CategoryRepository) FindByID(ctx context.Context, id string) (*models.Category, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid category ID")
+	}
+
+	var category models.Category
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&category)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("category not found")
+		}
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// Exists indica si existe una categoría con el ID dado, usado para validar
+// Product.Category como referencia externa.
+func (r *CategoryRepository) Exists(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, nil
+	}
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// List devuelve todas las categorías que cumplen el filtro de status, ordenadas por sorter
+func (r *CategoryRepository) List(ctx context.Context, status string) ([]*models.Category, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "sorter", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var categories []*models.Category
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// Tree arma el árbol de categorías en memoria: trae todas las filas que cumplen el
+// filtro de status (ordenadas por sorter) y luego arma recursivamente los hijos
+// comparando parent_id == parent.id. Si root coincide con una fila, esa fila es el
+// único nodo superior con sus descendientes; si no coincide con ninguna, se devuelven
+// los hijos directos de root como slice de nivel superior.
+func (r *CategoryRepository) Tree(ctx context.Context, root, status string) ([]models.CategoryNested, error) {
+	all, err := r.List(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	byParent := make(map[string][]*models.Category)
+	for _, c := range all {
+		key := ""
+		if c.ParentID != nil {
+			key = c.ParentID.Hex()
+		}
+		byParent[key] = append(byParent[key], c)
+	}
+
+	var buildChildren func(parentKey string) []models.CategoryNested
+	buildChildren = func(parentKey string) []models.CategoryNested {
+		children := byParent[parentKey]
+		nested := make([]models.CategoryNested, 0, len(children))
+		for _, c := range children {
+			nested = append(nested, models.CategoryNested{
+				Category: *c,
+				Children: buildChildren(c.ID.Hex()),
+			})
+		}
+		return nested
+	}
+
+	if root == "" {
+		return buildChildren(""), nil
+	}
+
+	for _, c := range all {
+		if c.ID.Hex() == root {
+			return []models.CategoryNested{{
+				Category: *c,
+				Children: buildChildren(c.ID.Hex()),
+			}}, nil
+		}
+	}
+
+	return buildChildren(root), nil
+}
+
+// Update actualiza una categoría
+func (r *CategoryRepository) Update(ctx context.Context, id string, update bson.M) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid category ID")
+	}
+
+	update["updated_at"] = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": update})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("category not found")
+	}
+
+	return nil
+}
+
+// Delete elimina una categoría
+func (r *CategoryRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid category ID")
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("category not found")
+	}
+
+	return nil
+}
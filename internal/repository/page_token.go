@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PageToken codifica el punto de corte de una página de resultados por keyset,
+// evitando el drift de skip/limit bajo inserciones concurrentes.
+type PageToken struct {
+	SortField string      `json:"sort_field"`
+	LastValue interface{} `json:"last_value"`
+	LastID    string      `json:"last_id"`
+}
+
+// wirePageToken es la representación serializada de PageToken. LastValue pasa
+// por JSON como string/number/bool desnudo, lo que pierde el tipo original
+// (p.ej. time.Time se vuelve un string RFC3339 plano al decodificar en
+// interface{}), así que LastValueKind lo etiqueta explícitamente para poder
+// reconstituirlo.
+type wirePageToken struct {
+	SortField     string      `json:"sort_field"`
+	LastValue     interface{} `json:"last_value"`
+	LastValueKind string      `json:"last_value_kind"`
+	LastID        string      `json:"last_id"`
+}
+
+const (
+	kindTime = "time"
+	kindAny  = "any"
+)
+
+// EncodePageToken serializa un PageToken como base64-JSON opaco para el cliente
+func EncodePageToken(t PageToken) (string, error) {
+	w := wirePageToken{
+		SortField:     t.SortField,
+		LastID:        t.LastID,
+		LastValue:     t.LastValue,
+		LastValueKind: kindAny,
+	}
+	if ts, ok := t.LastValue.(time.Time); ok {
+		w.LastValueKind = kindTime
+		w.LastValue = ts.UTC().Format(time.RFC3339Nano)
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// DecodePageToken revierte EncodePageToken, reconstituyendo time.Time cuando
+// LastValueKind lo indica en vez de dejarlo como el string/number que produce
+// json.Unmarshal sobre interface{}.
+func DecodePageToken(token string) (PageToken, error) {
+	var t PageToken
+	var w wirePageToken
+	data, err := base64.URLEncoding.DecodeString(token)
+	if err != nil {
+		return t, fmt.Errorf("invalid page token")
+	}
+	if err := json.Unmarshal(data, &w); err != nil {
+		return t, fmt.Errorf("invalid page token")
+	}
+	t.SortField = w.SortField
+	t.LastID = w.LastID
+	t.LastValue = w.LastValue
+	if w.LastValueKind == kindTime {
+		str, ok := w.LastValue.(string)
+		if !ok {
+			return t, fmt.Errorf("invalid page token")
+		}
+		ts, err := time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			return t, fmt.Errorf("invalid page token")
+		}
+		t.LastValue = ts
+	}
+	return t, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestPageTokenRoundTripTime(t *testing.T) {
+	original := PageToken{
+		SortField: "created_at",
+		LastValue: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		LastID:    "507f1f77bcf86cd799439011",
+	}
+
+	encoded, err := EncodePageToken(original)
+	if err != nil {
+		t.Fatalf("EncodePageToken returned error: %v", err)
+	}
+
+	decoded, err := DecodePageToken(encoded)
+	if err != nil {
+		t.Fatalf("DecodePageToken returned error: %v", err)
+	}
+
+	ts, ok := decoded.LastValue.(time.Time)
+	if !ok {
+		t.Fatalf("LastValue is %T, want time.Time", decoded.LastValue)
+	}
+	if !ts.Equal(original.LastValue.(time.Time)) {
+		t.Errorf("LastValue = %v, want %v", ts, original.LastValue)
+	}
+	if decoded.SortField != original.SortField || decoded.LastID != original.LastID {
+		t.Errorf("decoded = %+v, want %+v", decoded, original)
+	}
+}
+
+func TestPageTokenRoundTripNonTime(t *testing.T) {
+	cases := []PageToken{
+		{SortField: "name", LastValue: "widget", LastID: "507f1f77bcf86cd799439011"},
+		{SortField: "price_cents", LastValue: float64(1999), LastID: "507f1f77bcf86cd799439011"},
+	}
+
+	for _, original := range cases {
+		encoded, err := EncodePageToken(original)
+		if err != nil {
+			t.Fatalf("EncodePageToken returned error: %v", err)
+		}
+
+		decoded, err := DecodePageToken(encoded)
+		if err != nil {
+			t.Fatalf("DecodePageToken returned error: %v", err)
+		}
+
+		if decoded.LastValue != original.LastValue {
+			t.Errorf("LastValue = %#v, want %#v", decoded.LastValue, original.LastValue)
+		}
+	}
+}
+
+func TestDecodePageTokenInvalid(t *testing.T) {
+	if _, err := DecodePageToken("not-valid-base64!!"); err == nil {
+		t.Error("expected error for invalid base64, got nil")
+	}
+
+	// last_value_kind says "time" but last_value isn't a parseable RFC3339 string
+	malformed := base64.URLEncoding.EncodeToString([]byte(
+		`{"sort_field":"created_at","last_value":"not-a-date","last_value_kind":"time","last_id":"x"}`))
+	if _, err := DecodePageToken(malformed); err == nil {
+		t.Error("expected error for unparseable time-tagged payload, got nil")
+	}
+}
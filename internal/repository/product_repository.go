@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -32,6 +34,7 @@ func (r *ProductRepository) Create(ctx context.Context, product *models.Product)
 	product.CreatedAt = time.Now()
 	product.UpdatedAt = time.Now()
 	product.IsDeleted = false
+	product.Version = 1
 
 	_, err := r.collection.InsertOne(ctx, product)
 	return err
@@ -154,8 +157,341 @@ func (r *ProductRepository) FindAll(ctx context.Context, page, pageSize int, cat
 	return products, total, nil
 }
 
-// Update actualiza un producto
-func (r *ProductRepository) Update(ctx context.Context, id string, update bson.M) error {
+// CategoryCount es el resultado de agrupar productos por categoría
+type CategoryCount struct {
+	Category string `bson:"_id" json:"category"`
+	Count    int64  `bson:"count" json:"count"`
+}
+
+// PriceBucket es un rango del histograma de precios
+type PriceBucket struct {
+	Boundary interface{} `bson:"_id" json:"boundary"`
+	Count    int64       `bson:"count" json:"count"`
+}
+
+// StockCounts resume disponibilidad de stock
+type StockCounts struct {
+	InStock    int64 `json:"in_stock"`
+	OutOfStock int64 `json:"out_of_stock"`
+}
+
+// AttributeValueCount es el resultado de agrupar por un valor de atributo dado
+type AttributeValueCount struct {
+	Value string `bson:"_id" json:"value"`
+	Count int64  `bson:"count" json:"count"`
+}
+
+// Facets es la respuesta agregada del endpoint de facetas
+type Facets struct {
+	Categories      []CategoryCount       `json:"categories"`
+	PriceHistogram  []PriceBucket         `json:"price_histogram"`
+	Stock           StockCounts           `json:"stock"`
+	AttributeValues []AttributeValueCount `json:"attribute_values,omitempty"`
+}
+
+type facetsRaw struct {
+	Categories     []CategoryCount `bson:"categories"`
+	PriceHistogram []PriceBucket   `bson:"price_histogram"`
+	Stock          []struct {
+		ID    string `bson:"_id"`
+		Count int64  `bson:"count"`
+	} `bson:"stock"`
+	AttributeValues []AttributeValueCount `bson:"attribute_values"`
+}
+
+// Facets calcula en una sola llamada de agregación ($facet) los conteos por
+// categoría, un histograma de precios, disponibilidad de stock y, si se pide,
+// los valores más frecuentes de un atributo dinámico — el patrón clásico de
+// "left rail" de un catálogo de e-commerce.
+func (r *ProductRepository) Facets(ctx context.Context, filter bson.M, priceBoundaries []int64, attributeKey string, topN int) (*Facets, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	boundaries := make(bson.A, len(priceBoundaries))
+	for i, b := range priceBoundaries {
+		boundaries[i] = b
+	}
+
+	facetStages := bson.M{
+		"categories": bson.A{
+			bson.M{"$group": bson.M{"_id": "$category", "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.M{"count": -1}},
+		},
+		"price_histogram": bson.A{
+			bson.M{"$bucket": bson.M{
+				"groupBy":    "$price_cents",
+				"boundaries": boundaries,
+				"default":    "other",
+				"output":     bson.M{"count": bson.M{"$sum": 1}},
+			}},
+		},
+		"stock": bson.A{
+			bson.M{"$group": bson.M{
+				"_id":   bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$stock", 0}}, "in_stock", "out_of_stock"}},
+				"count": bson.M{"$sum": 1},
+			}},
+		},
+	}
+
+	if attributeKey != "" {
+		if topN <= 0 {
+			topN = 10
+		}
+		facetStages["attribute_values"] = bson.A{
+			bson.M{"$project": bson.M{"attrs": bson.M{"$objectToArray": "$attributes"}}},
+			bson.M{"$unwind": "$attrs"},
+			bson.M{"$match": bson.M{"attrs.k": attributeKey}},
+			bson.M{"$group": bson.M{"_id": "$attrs.v", "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.M{"count": -1}},
+			bson.M{"$limit": int64(topN)},
+		}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: facetStages}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var raw []facetsRaw
+	if err := cursor.All(ctx, &raw); err != nil {
+		return nil, err
+	}
+
+	result := &Facets{Categories: []CategoryCount{}, PriceHistogram: []PriceBucket{}}
+	if len(raw) > 0 {
+		result.Categories = raw[0].Categories
+		result.PriceHistogram = raw[0].PriceHistogram
+		result.AttributeValues = raw[0].AttributeValues
+		for _, s := range raw[0].Stock {
+			switch s.ID {
+			case "in_stock":
+				result.Stock.InStock = s.Count
+			case "out_of_stock":
+				result.Stock.OutOfStock = s.Count
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// textSearchCompatible determina si q puede pasarse directamente a un $text search:
+// las comillas deben estar balanceadas (delimitan frases) y no debe contener
+// caracteres de operador de bson que romperían el parseo de la consulta.
+func textSearchCompatible(q string) bool {
+	if strings.Count(q, `"`)%2 != 0 {
+		return false
+	}
+	for _, c := range []string{"$", "{", "}"} {
+		if strings.Contains(q, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSearchSortField reporta si field es una clave de ordenamiento válida para
+// Search (las mismas que FindAllCursor), para que el handler pueda rechazar
+// valores desconocidos con 400 antes de tocar la base de datos.
+func IsSearchSortField(field string) bool {
+	_, ok := sortableFields[field]
+	return ok
+}
+
+// IsTextSearchQuery reporta si q correría en modo $text (no forceRegex y compatible
+// con el operador), para que el handler pueda validar sort_by=score con un 400
+// antes de llamar a Search, en vez de dejar que el repositorio lo rechace con un error genérico.
+func IsTextSearchQuery(q string, forceRegex bool) bool {
+	return q != "" && !forceRegex && textSearchCompatible(q)
+}
+
+// Search busca productos por texto usando el índice de texto en name/description
+// (Collection.$text) cuando la consulta es compatible, y cae a $regex en caso
+// contrario o cuando forceRegex lo pide explícitamente. filter ya trae aplicados
+// los filtros estructurados del caller (categorías, rango de precio, stock, tags,
+// is_deleted); Search sólo agrega la condición de texto y calcula, sobre ese mismo
+// filtro, los facets de categoría y precio para que reflejen la vista filtrada.
+func (r *ProductRepository) Search(ctx context.Context, filter bson.M, q, sortBy, sortOrder string, page, pageSize int, forceRegex bool, priceBoundaries []int64) ([]*models.Product, int64, *Facets, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	useText := q != "" && !forceRegex && textSearchCompatible(q)
+	if q != "" {
+		if useText {
+			filter["$text"] = bson.M{"$search": q}
+		} else {
+			filter["$or"] = []bson.M{
+				{"name": bson.M{"$regex": q, "$options": "i"}},
+				{"description": bson.M{"$regex": q, "$options": "i"}},
+			}
+		}
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	skip := (page - 1) * pageSize
+
+	opts := options.Find().SetSkip(int64(skip)).SetLimit(int64(pageSize))
+
+	direction := -1
+	if sortOrder == "asc" {
+		direction = 1
+	}
+
+	switch {
+	case sortBy == "score" || (sortBy == "" && useText):
+		if !useText {
+			return nil, 0, nil, fmt.Errorf("sort_by=score requires a text search query")
+		}
+		opts.SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}})
+		opts.SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}})
+	case sortBy == "":
+		opts.SetSort(bson.D{{Key: "created_at", Value: direction}})
+	default:
+		opts.SetSort(bson.D{{Key: sortBy, Value: direction}})
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var products []*models.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, 0, nil, err
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return products, 0, nil, err
+	}
+
+	facets, err := r.Facets(ctx, filter, priceBoundaries, "", 0)
+	if err != nil {
+		return products, total, nil, err
+	}
+
+	return products, total, facets, nil
+}
+
+// sortableFields son los únicos campos que FindAllCursor admite como clave de
+// ordenamiento, ya que cada uno necesita saber cómo extraer su valor del documento.
+var sortableFields = map[string]func(*models.Product) interface{}{
+	"name":        func(p *models.Product) interface{} { return p.Name },
+	"price_cents": func(p *models.Product) interface{} { return p.PriceCents },
+	"stock":       func(p *models.Product) interface{} { return p.Stock },
+	"category":    func(p *models.Product) interface{} { return p.Category },
+	"created_at":  func(p *models.Product) interface{} { return p.CreatedAt },
+}
+
+// FindAllCursor implementa paginación por keyset: en vez de skip/limit, cada página
+// referencia el último valor visto (más el _id como desempate) para pedir la siguiente
+// tanda, lo que evita el drift de skip/limit en colecciones grandes con escrituras concurrentes.
+func (r *ProductRepository) FindAllCursor(ctx context.Context, pageSize int, category, sortBy, sortOrder, pageToken string) ([]*models.Product, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	sortField := sortBy
+	if sortField == "" {
+		sortField = "created_at"
+	}
+	extractSortValue, ok := sortableFields[sortField]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported sort field %q", sortField)
+	}
+
+	direction := -1
+	if sortOrder == "asc" {
+		direction = 1
+	}
+	cmpOp := "$lt"
+	if direction == 1 {
+		cmpOp = "$gt"
+	}
+
+	filter := bson.M{"is_deleted": false}
+	if category != "" {
+		filter["category"] = category
+	}
+
+	if pageToken != "" {
+		tok, err := DecodePageToken(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		if tok.SortField != sortField {
+			return nil, "", fmt.Errorf("page token does not match sort field %q", sortField)
+		}
+		lastID, err := primitive.ObjectIDFromHex(tok.LastID)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid page token")
+		}
+		filter["$or"] = []bson.M{
+			{sortField: bson.M{cmpOp: tok.LastValue}},
+			{sortField: tok.LastValue, "_id": bson.M{cmpOp: lastID}},
+		}
+	}
+
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	opts := options.Find().
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: sortField, Value: direction}, {Key: "_id", Value: direction}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	var products []*models.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(products) == pageSize {
+		last := products[len(products)-1]
+		nextToken, err = EncodePageToken(PageToken{
+			SortField: sortField,
+			LastValue: extractSortValue(last),
+			LastID:    last.ID.Hex(),
+		})
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	return products, nextToken, nil
+}
+
+// VersionConflictError indica que expectedVersion ya no coincide con la versión
+// actual del documento, y trae la versión vigente para que el caller pueda reintentar.
+type VersionConflictError struct {
+	CurrentVersion int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict: current version is %d", e.CurrentVersion)
+}
+
+// Update actualiza un producto. Si expectedVersion no es nil, la actualización sólo
+// se aplica si coincide con el campo version del documento (control de concurrencia
+// optimista); en caso contrario se devuelve VersionConflictError con la versión vigente.
+func (r *ProductRepository) Update(ctx context.Context, id string, update bson.M, expectedVersion *int64) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -171,11 +507,14 @@ func (r *ProductRepository) Update(ctx context.Context, id string, update bson.M
 		"_id":        objID,
 		"is_deleted": false,
 	}
+	if expectedVersion != nil {
+		filter["version"] = *expectedVersion
+	}
 
 	result, err := r.collection.UpdateOne(
 		ctx,
 		filter,
-		bson.M{"$set": update},
+		bson.M{"$set": update, "$inc": bson.M{"version": 1}},
 	)
 
 	if err != nil {
@@ -183,12 +522,230 @@ func (r *ProductRepository) Update(ctx context.Context, id string, update bson.M
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("product not found")
+		if expectedVersion == nil {
+			return fmt.Errorf("product not found")
+		}
+
+		var current models.Product
+		err := r.collection.FindOne(ctx, bson.M{"_id": objID, "is_deleted": false}).Decode(&current)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return fmt.Errorf("product not found")
+			}
+			return err
+		}
+		return &VersionConflictError{CurrentVersion: current.Version}
 	}
 
 	return nil
 }
 
+// BulkOperation representa una operación individual dentro de un BulkWrite
+type BulkOperation struct {
+	Op   string                 `json:"op"`
+	SKU  string                 `json:"sku,omitempty"`
+	ID   string                 `json:"id,omitempty"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// BulkOpResult reporta el resultado de una operación individual del batch
+type BulkOpResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// bulkFilter construye el filtro de selección de una operación por ID o SKU
+func bulkFilter(op BulkOperation) (bson.M, error) {
+	if op.ID != "" {
+		objID, err := primitive.ObjectIDFromHex(op.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id")
+		}
+		return bson.M{"_id": objID, "is_deleted": false}, nil
+	}
+	if op.SKU != "" {
+		return bson.M{"sku": op.SKU, "is_deleted": false}, nil
+	}
+	return nil, fmt.Errorf("id or sku is required")
+}
+
+// buildBulkWriteModel traduce una BulkOperation en el WriteModel de mongo correspondiente
+func buildBulkWriteModel(op BulkOperation) (mongo.WriteModel, string, error) {
+	switch op.Op {
+	case "insert":
+		id := primitive.NewObjectID()
+		doc := bson.M{
+			"_id":         id,
+			"sku":         op.Data["sku"],
+			"name":        op.Data["name"],
+			"description": op.Data["description"],
+			"category":    op.Data["category"],
+			"price_cents": op.Data["price_cents"],
+			"currency":    op.Data["currency"],
+			"stock":       op.Data["stock"],
+			"images":      op.Data["images"],
+			"attributes":  op.Data["attributes"],
+			"is_active":   op.Data["is_active"],
+			"is_deleted":  false,
+			"created_at":  time.Now(),
+			"updated_at":  time.Now(),
+			"version":     int64(1),
+		}
+		return mongo.NewInsertOneModel().SetDocument(doc), id.Hex(), nil
+
+	case "update":
+		filter, err := bulkFilter(op)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(op.Data) == 0 {
+			return nil, "", fmt.Errorf("data is required")
+		}
+		update := bson.M{}
+		for k, v := range op.Data {
+			update[k] = v
+		}
+		update["updated_at"] = time.Now()
+		model := mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(bson.M{"$set": update, "$inc": bson.M{"version": 1}})
+		return model, op.ID, nil
+
+	case "upsert":
+		filter, err := bulkFilter(op)
+		if err != nil {
+			return nil, "", err
+		}
+		update := bson.M{}
+		for k, v := range op.Data {
+			update[k] = v
+		}
+		update["updated_at"] = time.Now()
+		model := mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{
+				"$set":         update,
+				"$setOnInsert": bson.M{"created_at": time.Now(), "is_deleted": false},
+				// $inc on a missing field starts it at 0, so this also seeds
+				// version=1 on the insert branch without colliding with
+				// $setOnInsert on the same field.
+				"$inc": bson.M{"version": 1},
+			}).
+			SetUpsert(true)
+		return model, op.ID, nil
+
+	case "delete":
+		filter, err := bulkFilter(op)
+		if err != nil {
+			return nil, "", err
+		}
+		model := mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{
+				"$set": bson.M{"is_deleted": true, "updated_at": time.Now()},
+				"$inc": bson.M{"version": 1},
+			})
+		return model, op.ID, nil
+
+	default:
+		return nil, "", fmt.Errorf("unknown op %q", op.Op)
+	}
+}
+
+// BulkWrite ejecuta un lote de inserciones/actualizaciones/eliminaciones en una sola llamada
+// a la base de datos, reportando el resultado de cada operación individualmente.
+func (r *ProductRepository) BulkWrite(ctx context.Context, ops []BulkOperation, ordered bool) ([]BulkOpResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	results := make([]BulkOpResult, len(ops))
+	for i := range ops {
+		results[i].Index = i
+	}
+
+	writeModels := make([]mongo.WriteModel, 0, len(ops))
+	modelIndex := make([]int, 0, len(ops))
+
+	for i, op := range ops {
+		model, id, err := buildBulkWriteModel(op)
+		if err != nil {
+			results[i].Status = "error"
+			results[i].Error = err.Error()
+			if ordered {
+				// Mirrors the mongo-level ordered-abort case below: nothing
+				// after a pre-flight validation failure was ever attempted.
+				for j := i + 1; j < len(ops); j++ {
+					results[j].Status = "skipped"
+				}
+				break
+			}
+			continue
+		}
+		results[i].ID = id
+		writeModels = append(writeModels, model)
+		modelIndex = append(modelIndex, i)
+	}
+
+	if len(writeModels) == 0 {
+		return results, nil
+	}
+
+	bulkOpts := options.BulkWrite().SetOrdered(ordered)
+	res, err := r.collection.BulkWrite(ctx, writeModels, bulkOpts)
+
+	if res != nil {
+		for modelPos, upsertedID := range res.UpsertedIDs {
+			origIndex := modelIndex[modelPos]
+			if oid, ok := upsertedID.(primitive.ObjectID); ok {
+				results[origIndex].ID = oid.Hex()
+			}
+		}
+	}
+
+	var bwErr mongo.BulkWriteException
+	if errors.As(err, &bwErr) {
+		erroredPos := make(map[int]bool, len(bwErr.WriteErrors))
+		firstErrPos := -1
+		for _, we := range bwErr.WriteErrors {
+			erroredPos[we.Index] = true
+			if firstErrPos == -1 || we.Index < firstErrPos {
+				firstErrPos = we.Index
+			}
+			origIndex := modelIndex[we.Index]
+			results[origIndex].Status = "error"
+			results[origIndex].Error = we.Message
+		}
+		for modelPos, origIndex := range modelIndex {
+			if erroredPos[modelPos] {
+				continue
+			}
+			switch {
+			case ordered && modelPos > firstErrPos:
+				// MongoDB aborts an ordered batch at the first error, so
+				// everything queued after it was never sent to the server.
+				results[origIndex].Status = "skipped"
+			default:
+				results[origIndex].Status = "ok"
+			}
+		}
+		return results, nil
+	}
+	if err != nil {
+		// Not a partial per-op failure (BulkWriteException) but something like a
+		// timeout or dropped connection: we can't tell which ops actually landed,
+		// so leave their status unset rather than claim "ok".
+		return results, err
+	}
+
+	for _, id := range modelIndex {
+		if results[id].Status == "" {
+			results[id].Status = "ok"
+		}
+	}
+
+	return results, nil
+}
+
 // SoftDelete marca un producto como eliminado
 func (r *ProductRepository) SoftDelete(ctx context.Context, id string) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -209,6 +766,7 @@ func (r *ProductRepository) SoftDelete(ctx context.Context, id string) error {
 			"is_deleted": true,
 			"updated_at": time.Now(),
 		},
+		"$inc": bson.M{"version": 1},
 	}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
@@ -221,4 +779,23 @@ func (r *ProductRepository) SoftDelete(ctx context.Context, id string) error {
 	}
 
 	return nil
+}
+
+// PurgeDeleted elimina definitivamente los documentos marcados como is_deleted
+// cuya última actualización sea anterior a olderThan, liberando el soft-delete
+// acumulado por SoftDelete/BulkWrite una vez pasó la ventana de retención.
+func (r *ProductRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	cutoff := time.Now().Add(-olderThan)
+	result, err := r.collection.DeleteMany(ctx, bson.M{
+		"is_deleted": true,
+		"updated_at": bson.M{"$lt": cutoff},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
 }
\ No newline at end of file
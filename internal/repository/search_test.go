@@ -0,0 +1,38 @@
+package repository
+
+import "testing"
+
+func TestTextSearchCompatible(t *testing.T) {
+	cases := []struct {
+		q    string
+		want bool
+	}{
+		{`red shoes`, true},
+		{`"red shoes"`, true},
+		{`"unbalanced quote`, false},
+		{`price $gt 10`, false},
+		{`{"$where": "1"}`, false},
+		{``, true},
+	}
+
+	for _, tc := range cases {
+		if got := textSearchCompatible(tc.q); got != tc.want {
+			t.Errorf("textSearchCompatible(%q) = %v, want %v", tc.q, got, tc.want)
+		}
+	}
+}
+
+func TestIsTextSearchQuery(t *testing.T) {
+	if !IsTextSearchQuery("red shoes", false) {
+		t.Error("expected plain query with forceRegex=false to be text-search compatible")
+	}
+	if IsTextSearchQuery("red shoes", true) {
+		t.Error("forceRegex=true should never report as text-search compatible")
+	}
+	if IsTextSearchQuery("", false) {
+		t.Error("empty query should never report as text-search compatible")
+	}
+	if IsTextSearchQuery(`price $gt 10`, false) {
+		t.Error("query with bson operator characters should not be text-search compatible")
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"product-catalog/internal/models"
+)
+
+// maxTxnRetries limita los reintentos de transacción y de commit
+const maxTxnRetries = 3
+
+// ProductService agrupa operaciones que abarcan varios documentos y requieren
+// atomicidad, algo que ProductRepository (pensado para un solo documento) no cubre.
+type ProductService struct {
+	client             *mongo.Client
+	collection         *mongo.Collection
+	categoryCollection *mongo.Collection
+}
+
+func NewProductService(client *mongo.Client, collection, categoryCollection *mongo.Collection) *ProductService {
+	return &ProductService{client: client, collection: collection, categoryCollection: categoryCollection}
+}
+
+// StockAdjustment representa el cambio de stock a aplicar a un SKU
+type StockAdjustment struct {
+	SKU string `json:"sku" binding:"required"`
+	Qty int    `json:"qty"`
+}
+
+// AdjustStock decrementa (o incrementa) el stock de varios SKUs de forma atómica:
+// si cualquier item dejaría el stock en negativo, se aborta todo el lote.
+func (s *ProductService) AdjustStock(ctx context.Context, items []StockAdjustment) error {
+	session, err := s.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	return runTransactionWithRetry(ctx, session, func(sessCtx mongo.SessionContext) error {
+		for _, item := range items {
+			var product models.Product
+			err := s.collection.FindOne(sessCtx, bson.M{"sku": item.SKU, "is_deleted": false}).Decode(&product)
+			if err != nil {
+				if err == mongo.ErrNoDocuments {
+					return fmt.Errorf("sku %q not found", item.SKU)
+				}
+				return err
+			}
+
+			newStock := product.Stock + item.Qty
+			if newStock < 0 {
+				return fmt.Errorf("adjustment would leave sku %q with negative stock", item.SKU)
+			}
+
+			_, err = s.collection.UpdateOne(sessCtx,
+				bson.M{"sku": item.SKU},
+				bson.M{
+					"$set": bson.M{"stock": newStock, "updated_at": time.Now()},
+					"$inc": bson.M{"version": 1},
+				})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RenameCategory busca la categoría por slug, renombra su campo Name y reescribe
+// todos los productos que la referencian (por Category.ID) en una sola transacción.
+// Desde que Product.Category pasó a ser un ObjectID de Category (ver category_exists),
+// el slug identifica a la categoría y su _id es lo que realmente referencian los
+// productos, así que ninguno de los dos cambia aquí.
+func (s *ProductService) RenameCategory(ctx context.Context, slug, newName string) error {
+	session, err := s.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	return runTransactionWithRetry(ctx, session, func(sessCtx mongo.SessionContext) error {
+		var category models.Category
+		err := s.categoryCollection.FindOne(sessCtx, bson.M{"slug": slug}).Decode(&category)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return fmt.Errorf("category %q not found", slug)
+			}
+			return err
+		}
+
+		if _, err := s.categoryCollection.UpdateOne(sessCtx,
+			bson.M{"_id": category.ID},
+			bson.M{"$set": bson.M{"name": newName, "updated_at": time.Now()}}); err != nil {
+			return err
+		}
+
+		_, err = s.collection.UpdateMany(sessCtx,
+			bson.M{"category": category.ID.Hex()},
+			bson.M{
+				"$set": bson.M{"updated_at": time.Now()},
+				"$inc": bson.M{"version": 1},
+			})
+		return err
+	})
+}
+
+// runTransactionWithRetry ejecuta fn dentro de una transacción, reintentando el bloque
+// completo ante TransientTransactionError y el commit ante UnknownTransactionCommitResult,
+// siguiendo el patrón de reintento documentado por el driver de mongo.
+func runTransactionWithRetry(ctx context.Context, session mongo.Session, fn func(sessCtx mongo.SessionContext) error) error {
+	for attempt := 0; attempt < maxTxnRetries; attempt++ {
+		if err := session.StartTransaction(); err != nil {
+			return err
+		}
+
+		txnErr := mongo.WithSession(ctx, session, func(sessCtx mongo.SessionContext) error {
+			if err := fn(sessCtx); err != nil {
+				_ = session.AbortTransaction(sessCtx)
+				return err
+			}
+			return commitWithRetry(sessCtx, session)
+		})
+
+		if txnErr == nil {
+			return nil
+		}
+
+		var cmdErr mongo.CommandError
+		if errors.As(txnErr, &cmdErr) && cmdErr.HasErrorLabel("TransientTransactionError") {
+			continue
+		}
+		return txnErr
+	}
+	return fmt.Errorf("transaction failed after %d retries", maxTxnRetries)
+}
+
+// commitWithRetry reintenta el commit mientras el driver reporte UnknownTransactionCommitResult
+func commitWithRetry(ctx context.Context, session mongo.Session) error {
+	for attempt := 0; attempt < maxTxnRetries; attempt++ {
+		err := session.CommitTransaction(ctx)
+		if err == nil {
+			return nil
+		}
+
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) && cmdErr.HasErrorLabel("UnknownTransactionCommitResult") {
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("commit failed after %d retries", maxTxnRetries)
+}
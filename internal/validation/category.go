@@ -0,0 +1,23 @@
+package validation
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"product-catalog/internal/repository"
+)
+
+// RegisterCategoryExists registra la regla category_exists, que confirma contra
+// la base de datos que el valor del campo referencia una categoría existente.
+// Se llama una única vez al arrancar, cuando ya existe un CategoryRepository.
+func RegisterCategoryExists(repo *repository.CategoryRepository) error {
+	return Validate.RegisterValidation("category_exists", func(fl validator.FieldLevel) bool {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		exists, err := repo.Exists(ctx, fl.Field().String())
+		return err == nil && exists
+	})
+}
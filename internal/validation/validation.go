@@ -0,0 +1,107 @@
+// Package validation centraliza la validación de structs de entrada con
+// go-playground/validator, para que los handlers no filtren mensajes de error
+// de Go ni reimplementen las mismas reglas de campo cada uno por su cuenta.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Validate es la instancia compartida usada por todos los handlers
+var Validate = validator.New()
+
+var skuPattern = regexp.MustCompile(`^[A-Z0-9]{3,}(-[A-Z0-9]+)*$`)
+
+func init() {
+	// Sin esto, FieldError.Field devuelve el nombre del campo Go (p.ej.
+	// "PriceCents") en vez del json tag que el cliente realmente envió
+	// ("price_cents"), obligándolo a adivinar el mapeo.
+	Validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	if err := Validate.RegisterValidation("sku", validateSKU); err != nil {
+		panic(fmt.Sprintf("validation: failed to register sku rule: %v", err))
+	}
+}
+
+func validateSKU(fl validator.FieldLevel) bool {
+	return skuPattern.MatchString(fl.Field().String())
+}
+
+// FieldError es la representación pública de un fallo de validación de un
+// campo, sin exponer tipos internos del validador
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Param   string `json:"param"`
+	Message string `json:"message"`
+}
+
+// GetValidationErrors convierte un error de validator.ValidationErrors en FieldError
+// legibles por el cliente. Devuelve nil si err no proviene del validador.
+func GetValidationErrors(err error) []FieldError {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+
+	fieldErrors := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   fe.Field(),
+			Tag:     fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldErrorMessage(fe),
+		})
+	}
+	return fieldErrors
+}
+
+func fieldErrorMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", fe.Field(), fe.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", fe.Field(), fe.Param())
+	case "gte":
+		return fmt.Sprintf("%s must be greater than or equal to %s", fe.Field(), fe.Param())
+	case "len":
+		return fmt.Sprintf("%s must be exactly %s characters long", fe.Field(), fe.Param())
+	case "sku":
+		return fmt.Sprintf("%s must match the SKU pattern (uppercase letters, digits and hyphens)", fe.Field())
+	case "category_exists":
+		return fmt.Sprintf("%s does not reference an existing category", fe.Field())
+	default:
+		return fmt.Sprintf("%s failed validation on %q", fe.Field(), fe.Tag())
+	}
+}
+
+// FieldsForKeys traduce claves JSON presentes en un PATCH a los nombres de campo Go
+// que StructPartial necesita, para validar únicamente lo que el cliente envió.
+func FieldsForKeys(t reflect.Type, keys map[string]bool) []string {
+	fields := make([]string, 0, len(keys))
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if jsonTag == "" || jsonTag == "-" {
+			continue
+		}
+		if keys[jsonTag] {
+			fields = append(fields, field.Name)
+		}
+	}
+	return fields
+}